@@ -0,0 +1,92 @@
+// Package backends implements the pluggable ASR/translation backends used by
+// the auto-caption plugin. Each backend knows how to start a captioning job
+// against its own service and report progress back to the caller; how it
+// does so (HTTP polling, websocket streaming, a local subprocess) is an
+// implementation detail hidden behind the CaptionBackend interface.
+package backends
+
+import "context"
+
+// StartRequest carries the parameters needed to start a captioning job,
+// independent of which backend ultimately services it.
+type StartRequest struct {
+	VideoPath   string
+	Language    string
+	TranslateTo []string
+}
+
+// Progress is a single progress update for a running (or finished) job.
+type Progress struct {
+	Stage       string
+	Percent     float64
+	PartialText string
+	Done        bool
+	// ResultPath is the primary caption file produced by the job, kept for
+	// callers only interested in a single track. ResultPaths holds every
+	// file produced, e.g. the original transcription plus any translations
+	// requested via StartRequest.TranslateTo.
+	ResultPath  string
+	ResultPaths []string
+	Err         error
+}
+
+// CaptionBackend is implemented by each ASR/translation backend supported by
+// the plugin.
+type CaptionBackend interface {
+	// Name identifies the backend for logging and plugin config selection.
+	Name() string
+
+	// Start kicks off captioning for req.VideoPath and returns an opaque
+	// task ID used by Status/Stream to refer to the job.
+	Start(ctx context.Context, req StartRequest) (taskID string, err error)
+
+	// Status returns the current state of a previously started job.
+	Status(ctx context.Context, taskID string) (Progress, error)
+
+	// Stream pushes progress updates to onProgress as they become available
+	// until the job completes, fails, or ctx is cancelled. Backends that
+	// can't stream natively synthesize updates by polling Status.
+	Stream(ctx context.Context, taskID string, onProgress func(Progress)) error
+}
+
+// LanguageProber is implemented by backends that can detect a video's
+// spoken language from a short audio sample without running a full
+// transcription. Callers type-assert a CaptionBackend against this
+// interface since not every backend exposes a detection endpoint.
+type LanguageProber interface {
+	DetectLanguage(ctx context.Context, videoPath string) (string, error)
+}
+
+// Config is the subset of plugin configuration needed to construct a
+// backend. Fields are populated from the plugin's stash configuration
+// (getPluginConfiguration) and are backend-specific: a backend ignores the
+// fields it doesn't need.
+type Config struct {
+	ServiceURL string
+	BinaryPath string
+	APIKey     string
+	Model      string
+}
+
+// New constructs the named backend from cfg. Supported names are "native",
+// "whisper-cpp", "faster-whisper" and "openai".
+func New(name string, cfg Config) (CaptionBackend, error) {
+	switch name {
+	case "", "native":
+		return newNativeBackend(cfg), nil
+	case "whisper-cpp":
+		return newWhisperCppBackend(cfg), nil
+	case "faster-whisper":
+		return newFasterWhisperBackend(cfg), nil
+	case "openai":
+		return newOpenAIBackend(cfg), nil
+	default:
+		return nil, errUnknownBackend(name)
+	}
+}
+
+type errUnknownBackend string
+
+func (e errUnknownBackend) Error() string {
+	return "unknown caption backend: " + string(e)
+}
@@ -0,0 +1,102 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// fasterWhisperBackend talks to a faster-whisper Python service exposing a
+// REST API shaped like the native service (POST /transcribe, GET
+// /status/{id}), just under different routes.
+type fasterWhisperBackend struct {
+	serviceURL string
+	client     *http.Client
+}
+
+func newFasterWhisperBackend(cfg Config) *fasterWhisperBackend {
+	return &fasterWhisperBackend{
+		serviceURL: cfg.ServiceURL,
+		client:     &http.Client{},
+	}
+}
+
+func (b *fasterWhisperBackend) Name() string { return "faster-whisper" }
+
+func (b *fasterWhisperBackend) Start(ctx context.Context, req StartRequest) (string, error) {
+	url := fmt.Sprintf("%s/transcribe", b.serviceURL)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"audio_path":   req.VideoPath,
+		"language":     req.Language,
+		"translate_to": req.TranslateTo,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var taskResp taskStartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&taskResp); err != nil {
+		return "", err
+	}
+
+	return taskResp.TaskID, nil
+}
+
+func (b *fasterWhisperBackend) Status(ctx context.Context, taskID string) (Progress, error) {
+	url := fmt.Sprintf("%s/status/%s", b.serviceURL, taskID)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return Progress{}, err
+	}
+	defer resp.Body.Close()
+
+	var status taskStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return Progress{}, fmt.Errorf("failed to decode status: %w", err)
+	}
+
+	return statusToProgress(status), nil
+}
+
+func (b *fasterWhisperBackend) Stream(ctx context.Context, taskID string, onProgress func(Progress)) error {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			progress, err := b.Status(ctx, taskID)
+			if err != nil {
+				return err
+			}
+			onProgress(progress)
+			if progress.Done || progress.Err != nil {
+				return progress.Err
+			}
+		}
+	}
+}
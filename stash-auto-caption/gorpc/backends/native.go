@@ -0,0 +1,365 @@
+package backends
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// nativeBackend talks to the bespoke /auto-caption/* HTTP service shipped
+// alongside this plugin. It is the original (and default) backend.
+type nativeBackend struct {
+	serviceURL string
+	client     *http.Client
+}
+
+func newNativeBackend(cfg Config) *nativeBackend {
+	return &nativeBackend{
+		serviceURL: cfg.ServiceURL,
+		client:     &http.Client{},
+	}
+}
+
+func (b *nativeBackend) Name() string { return "native" }
+
+type taskStartRequest struct {
+	VideoPath   string   `json:"video_path"`
+	Language    string   `json:"language"`
+	TranslateTo []string `json:"translate_to,omitempty"`
+}
+
+type taskStartResponse struct {
+	TaskID string `json:"task_id"`
+	Status string `json:"status"`
+}
+
+type taskStatusResponse struct {
+	TaskID   string                 `json:"task_id"`
+	Status   string                 `json:"status"`
+	Progress float64                `json:"progress"`
+	Stage    *string                `json:"stage"`
+	Error    *string                `json:"error"`
+	Result   map[string]interface{} `json:"result"`
+}
+
+func (b *nativeBackend) Start(ctx context.Context, req StartRequest) (string, error) {
+	url := fmt.Sprintf("%s/auto-caption/start", b.serviceURL)
+
+	body, err := json.Marshal(taskStartRequest{
+		VideoPath:   req.VideoPath,
+		Language:    req.Language,
+		TranslateTo: req.TranslateTo,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var taskResp taskStartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&taskResp); err != nil {
+		return "", err
+	}
+
+	return taskResp.TaskID, nil
+}
+
+func (b *nativeBackend) Status(ctx context.Context, taskID string) (Progress, error) {
+	url := fmt.Sprintf("%s/auto-caption/status/%s", b.serviceURL, taskID)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return Progress{}, err
+	}
+	defer resp.Body.Close()
+
+	var status taskStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return Progress{}, fmt.Errorf("failed to decode status: %w", err)
+	}
+
+	return statusToProgress(status), nil
+}
+
+// probeDurationSeconds is how much audio DetectLanguage asks the service to
+// sample; long enough to be reliable, short enough to stay fast.
+const probeDurationSeconds = 30
+
+type detectLanguageRequest struct {
+	VideoPath       string `json:"video_path"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+type detectLanguageResponse struct {
+	Language string `json:"language"`
+}
+
+// DetectLanguage asks the service's /detect-language endpoint to identify a
+// video's spoken language from a short audio sample, without transcribing
+// the whole file.
+func (b *nativeBackend) DetectLanguage(ctx context.Context, videoPath string) (string, error) {
+	url := fmt.Sprintf("%s/auto-caption/detect-language", b.serviceURL)
+
+	body, err := json.Marshal(detectLanguageRequest{VideoPath: videoPath, DurationSeconds: probeDurationSeconds})
+	if err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var detected detectLanguageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&detected); err != nil {
+		return "", fmt.Errorf("failed to decode detect-language response: %w", err)
+	}
+
+	return detected.Language, nil
+}
+
+// Stream connects to the service's websocket progress endpoint and forwards
+// frames to onProgress, falling back to SSE and finally to backoff polling
+// if earlier transports aren't available. ctx is expected to be cancelled by
+// the caller (e.g. Stop) to abort whichever transport is currently active.
+func (b *nativeBackend) Stream(ctx context.Context, taskID string, onProgress func(Progress)) error {
+	wsURL := fmt.Sprintf("%s/auto-caption/ws/%s", httpToWS(b.serviceURL), taskID)
+
+	conn, _, err := websocket.Dial(ctx, wsURL, nil)
+	if err != nil {
+		return b.streamSSE(ctx, taskID, onProgress)
+	}
+	defer conn.CloseNow()
+
+	for {
+		var status taskStatusResponse
+		if err := wsjson.Read(ctx, conn, &status); err != nil {
+			conn.Close(websocket.StatusNormalClosure, "")
+			return b.streamSSE(ctx, taskID, onProgress)
+		}
+
+		progress := statusToProgress(status)
+		onProgress(progress)
+
+		if progress.Done || progress.Err != nil {
+			conn.Close(websocket.StatusNormalClosure, "")
+			return progress.Err
+		}
+	}
+}
+
+// sseEvent mirrors the fields the events endpoint sends per progress update.
+type sseEvent struct {
+	Stage       string  `json:"stage"`
+	Progress    float64 `json:"progress"`
+	PartialText string  `json:"partial_text"`
+	Done        bool    `json:"done"`
+	Error       *string `json:"error"`
+}
+
+// streamSSE consumes the service's server-sent-events progress endpoint,
+// falling back to backoff polling when the endpoint doesn't exist (404/405)
+// or the stream is interrupted.
+func (b *nativeBackend) streamSSE(ctx context.Context, taskID string, onProgress func(Progress)) error {
+	url := fmt.Sprintf("%s/auto-caption/events/%s", b.serviceURL, taskID)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return b.pollUntilDone(ctx, taskID, onProgress)
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return b.pollUntilDone(ctx, taskID, onProgress)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return b.pollUntilDone(ctx, taskID, onProgress)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return b.pollUntilDone(ctx, taskID, onProgress)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+
+		var event sseEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			continue
+		}
+
+		progress := Progress{Stage: event.Stage, Percent: event.Progress, PartialText: event.PartialText, Done: event.Done}
+		if event.Error != nil {
+			progress.Err = fmt.Errorf("caption generation failed: %s", *event.Error)
+		}
+
+		if progress.Done && progress.Err == nil {
+			// The SSE frame itself carries no result path, unlike the
+			// websocket and poll transports' taskStatusResponse, so fetch
+			// the final status to pick up ResultPath(s) before reporting
+			// completion.
+			final, err := b.Status(ctx, taskID)
+			if err != nil {
+				progress.Err = err
+			} else {
+				progress.ResultPath = final.ResultPath
+				progress.ResultPaths = final.ResultPaths
+			}
+		}
+
+		onProgress(progress)
+
+		if progress.Done || progress.Err != nil {
+			return progress.Err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// The stream closed without a terminal event; fall back to polling for
+	// the final status rather than assuming success.
+	return b.pollUntilDone(ctx, taskID, onProgress)
+}
+
+const (
+	minPollBackoff = 1 * time.Second
+	maxPollBackoff = 15 * time.Second
+)
+
+// pollUntilDone polls Status with jittered exponential backoff: the delay
+// doubles (capped at maxPollBackoff) each tick that reports no change in
+// stage or progress, and resets to minPollBackoff as soon as something moves
+// so short jobs still feel snappy.
+func (b *nativeBackend) pollUntilDone(ctx context.Context, taskID string, onProgress func(Progress)) error {
+	delay := minPollBackoff
+	var lastStage string
+	var lastPercent float64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitter(delay)):
+			progress, err := b.Status(ctx, taskID)
+			if err != nil {
+				return err
+			}
+			onProgress(progress)
+			if progress.Done || progress.Err != nil {
+				return progress.Err
+			}
+
+			if progress.Stage != lastStage || progress.Percent != lastPercent {
+				lastStage, lastPercent = progress.Stage, progress.Percent
+				delay = minPollBackoff
+			} else if delay *= 2; delay > maxPollBackoff {
+				delay = maxPollBackoff
+			}
+		}
+	}
+}
+
+// jitter randomizes d by up to +/-20% so many concurrent pollers don't
+// converge on the same tick.
+func jitter(d time.Duration) time.Duration {
+	const spread = 0.2
+	factor := 1 - spread + rand.Float64()*2*spread
+	return time.Duration(float64(d) * factor)
+}
+
+func statusToProgress(status taskStatusResponse) Progress {
+	progress := Progress{
+		Percent: status.Progress,
+	}
+	if status.Stage != nil {
+		progress.Stage = *status.Stage
+	}
+
+	switch status.Status {
+	case "completed":
+		progress.Done = true
+		if paths, ok := status.Result["caption_paths"].([]interface{}); ok {
+			for _, p := range paths {
+				if s, ok := p.(string); ok {
+					progress.ResultPaths = append(progress.ResultPaths, s)
+				}
+			}
+		}
+		if cp, ok := status.Result["caption_path"].(string); ok {
+			progress.ResultPath = cp
+			if len(progress.ResultPaths) == 0 {
+				progress.ResultPaths = []string{cp}
+			}
+		}
+	case "failed":
+		if status.Error != nil {
+			progress.Err = fmt.Errorf("caption generation failed: %s", *status.Error)
+		} else {
+			progress.Err = fmt.Errorf("caption generation failed")
+		}
+	}
+
+	return progress
+}
+
+func httpToWS(serviceURL string) string {
+	switch {
+	case len(serviceURL) >= 5 && serviceURL[:5] == "https":
+		return "wss" + serviceURL[5:]
+	case len(serviceURL) >= 4 && serviceURL[:4] == "http":
+		return "ws" + serviceURL[4:]
+	default:
+		return serviceURL
+	}
+}
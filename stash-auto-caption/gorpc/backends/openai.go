@@ -0,0 +1,186 @@
+package backends
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// openAIBackend talks to a generic OpenAI-compatible transcription endpoint
+// (OpenAI itself, LocalAI, faster-whisper-server, whisper.cpp-server, ...).
+// Transcription there is synchronous, so Start does the whole upload/wait
+// and Status/Stream just replay the cached result.
+type openAIBackend struct {
+	serviceURL string
+	apiKey     string
+	model      string
+	client     *http.Client
+
+	mu      sync.Mutex
+	results map[string]Progress
+}
+
+func newOpenAIBackend(cfg Config) *openAIBackend {
+	serviceURL := cfg.ServiceURL
+	if serviceURL == "" {
+		serviceURL = "https://api.openai.com/v1"
+	}
+	model := cfg.Model
+	if model == "" {
+		model = "whisper-1"
+	}
+	return &openAIBackend{
+		serviceURL: strings.TrimSuffix(serviceURL, "/"),
+		apiKey:     cfg.APIKey,
+		model:      model,
+		client:     &http.Client{},
+		results:    make(map[string]Progress),
+	}
+}
+
+func (b *openAIBackend) Name() string { return "openai" }
+
+func (b *openAIBackend) Start(ctx context.Context, req StartRequest) (string, error) {
+	taskID := req.VideoPath
+	base := strings.TrimSuffix(req.VideoPath, filepath.Ext(req.VideoPath))
+
+	srt, err := b.transcribe(ctx, "audio/transcriptions", req.VideoPath, req.Language)
+	if err != nil {
+		progress := Progress{Done: true, Err: fmt.Errorf("openai audio/transcriptions failed: %w", err)}
+		b.store(taskID, progress)
+		return taskID, progress.Err
+	}
+
+	resultPath := fmt.Sprintf("%s.%s.srt", base, req.Language)
+	if err := os.WriteFile(resultPath, srt, 0o644); err != nil {
+		progress := Progress{Done: true, Err: fmt.Errorf("failed to write caption file: %w", err)}
+		b.store(taskID, progress)
+		return taskID, progress.Err
+	}
+	resultPaths := []string{resultPath}
+
+	// The OpenAI-compatible /audio/translations endpoint only ever targets
+	// English, so it's the one translate_to entry this backend can actually
+	// service; every other requested language is silently out of reach here.
+	for _, lang := range req.TranslateTo {
+		if lang != "en" {
+			continue
+		}
+
+		translated, err := b.transcribe(ctx, "audio/translations", req.VideoPath, req.Language)
+		if err != nil {
+			progress := Progress{Done: true, Err: fmt.Errorf("openai audio/translations failed: %w", err)}
+			b.store(taskID, progress)
+			return taskID, progress.Err
+		}
+
+		translatedPath := fmt.Sprintf("%s.en.srt", base)
+		if err := os.WriteFile(translatedPath, translated, 0o644); err != nil {
+			progress := Progress{Done: true, Err: fmt.Errorf("failed to write translated caption file: %w", err)}
+			b.store(taskID, progress)
+			return taskID, progress.Err
+		}
+		resultPaths = append(resultPaths, translatedPath)
+	}
+
+	b.store(taskID, Progress{Done: true, Percent: 1, ResultPath: resultPath, ResultPaths: resultPaths})
+
+	return taskID, nil
+}
+
+// transcribe multipart-uploads videoPath to the given OpenAI-compatible
+// endpoint and returns the raw SRT response body.
+func (b *openAIBackend) transcribe(ctx context.Context, endpoint, videoPath, language string) ([]byte, error) {
+	file, err := os.Open(videoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	body := &strings.Builder{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", filepath.Base(videoPath))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, err
+	}
+
+	if err := writer.WriteField("model", b.model); err != nil {
+		return nil, err
+	}
+	if err := writer.WriteField("response_format", "srt"); err != nil {
+		return nil, err
+	}
+	// /audio/translations always targets English and doesn't accept a
+	// source language field.
+	if endpoint == "audio/transcriptions" && language != "" {
+		if err := writer.WriteField("language", language); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/%s", b.serviceURL, endpoint)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body.String()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	if b.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai %s returned %s: %s", endpoint, resp.Status, respBody)
+	}
+
+	return respBody, nil
+}
+
+func (b *openAIBackend) Status(ctx context.Context, taskID string) (Progress, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	progress, ok := b.results[taskID]
+	if !ok {
+		return Progress{}, fmt.Errorf("unknown openai task: %s", taskID)
+	}
+	return progress, nil
+}
+
+// Stream has nothing to wait for since Start already ran the job to
+// completion; it replays the cached result once.
+func (b *openAIBackend) Stream(ctx context.Context, taskID string, onProgress func(Progress)) error {
+	progress, err := b.Status(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	onProgress(progress)
+	return progress.Err
+}
+
+func (b *openAIBackend) store(taskID string, progress Progress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.results[taskID] = progress
+}
@@ -0,0 +1,99 @@
+package backends
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// whisperCppBackend runs captioning locally via a whisper.cpp `main`/`whisper-cli`
+// binary instead of talking to a remote service. Since the binary runs
+// synchronously, Start blocks until the subprocess exits and Status/Stream
+// simply replay the cached result.
+type whisperCppBackend struct {
+	binaryPath string
+
+	mu      sync.Mutex
+	results map[string]Progress
+}
+
+func newWhisperCppBackend(cfg Config) *whisperCppBackend {
+	binaryPath := cfg.BinaryPath
+	if binaryPath == "" {
+		binaryPath = "whisper-cli"
+	}
+	return &whisperCppBackend{
+		binaryPath: binaryPath,
+		results:    make(map[string]Progress),
+	}
+}
+
+func (b *whisperCppBackend) Name() string { return "whisper-cpp" }
+
+func (b *whisperCppBackend) Start(ctx context.Context, req StartRequest) (string, error) {
+	taskID := req.VideoPath
+	base := strings.TrimSuffix(req.VideoPath, filepath.Ext(req.VideoPath))
+
+	// whisper.cpp only supports translating to English, and does so in the
+	// same run rather than producing both an original-language transcript
+	// and a translation, so the output is labelled "en" in that case.
+	translateToEnglish := false
+	for _, lang := range req.TranslateTo {
+		if lang == "en" {
+			translateToEnglish = true
+		}
+	}
+
+	language := req.Language
+	if translateToEnglish {
+		language = "en"
+	}
+	outputPrefix := fmt.Sprintf("%s.%s", base, language)
+
+	args := []string{"-f", req.VideoPath, "-osrt", "-of", outputPrefix, "-l", req.Language}
+	if translateToEnglish {
+		args = append(args, "-tr")
+	}
+
+	cmd := exec.CommandContext(ctx, b.binaryPath, args...)
+	if err := cmd.Run(); err != nil {
+		progress := Progress{Done: true, Err: fmt.Errorf("whisper.cpp failed: %w", err)}
+		b.store(taskID, progress)
+		return taskID, progress.Err
+	}
+
+	resultPath := outputPrefix + ".srt"
+	b.store(taskID, Progress{Done: true, Percent: 1, ResultPath: resultPath, ResultPaths: []string{resultPath}})
+
+	return taskID, nil
+}
+
+func (b *whisperCppBackend) Status(ctx context.Context, taskID string) (Progress, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	progress, ok := b.results[taskID]
+	if !ok {
+		return Progress{}, fmt.Errorf("unknown whisper.cpp task: %s", taskID)
+	}
+	return progress, nil
+}
+
+// Stream has nothing to wait for since Start already ran the job to
+// completion; it replays the cached result once.
+func (b *whisperCppBackend) Stream(ctx context.Context, taskID string, onProgress func(Progress)) error {
+	progress, err := b.Status(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	onProgress(progress)
+	return progress.Err
+}
+
+func (b *whisperCppBackend) store(taskID string, progress Progress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.results[taskID] = progress
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/plugin/common/log"
+	"stash-auto-caption-rpc/backends"
+	"stash-auto-caption-rpc/logging"
+)
+
+// batchSceneResult is the outcome of queueing a single scene during a batch
+// run, keyed by scene so a caller parsing PluginOutput.Output can cross-
+// reference failures against Stash without re-running the batch.
+type batchSceneResult struct {
+	SceneID  string `json:"scene_id"`
+	Title    string `json:"title"`
+	Language string `json:"language,omitempty"`
+	Status   string `json:"status"` // succeeded, failed, skipped
+	Error    string `json:"error,omitempty"`
+}
+
+// batchSummary is the structured result of a generateBatch run, returned as
+// JSON in PluginOutput.Output so downstream tooling doesn't have to scrape
+// log lines.
+type batchSummary struct {
+	Queued    int                `json:"queued"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Skipped   int                `json:"skipped"`
+	Results   []batchSceneResult `json:"results"`
+}
+
+// marshalBatchSummary renders a batchSummary as JSON for PluginOutput.Output,
+// falling back to a minimal error payload if encoding somehow fails rather
+// than losing the run's outcome entirely.
+func marshalBatchSummary(summary batchSummary) string {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		log.Warnf("Failed to marshal batch summary: %v", err)
+		return `{"error":"failed to marshal batch summary"}`
+	}
+	return string(data)
+}
+
+func (s *batchSummary) record(result batchSceneResult) {
+	switch result.Status {
+	case "succeeded":
+		s.Succeeded++
+	case "failed":
+		s.Failed++
+	case "skipped":
+		s.Skipped++
+	}
+	s.Results = append(s.Results, result)
+}
+
+// rateLimiter is a token bucket capped at jobsPerMinute tokens, refilled one
+// at a time every minute/jobsPerMinute so a burst of queued scenes doesn't
+// all hit the captioning service in the same instant.
+type rateLimiter struct {
+	tokens chan struct{}
+	done   chan struct{}
+}
+
+// newRateLimiter returns nil (no limiting) when jobsPerMinute <= 0.
+func newRateLimiter(jobsPerMinute int) *rateLimiter {
+	if jobsPerMinute <= 0 {
+		return nil
+	}
+
+	rl := &rateLimiter{
+		tokens: make(chan struct{}, jobsPerMinute),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i < jobsPerMinute; i++ {
+		rl.tokens <- struct{}{}
+	}
+
+	go rl.refill(time.Minute / time.Duration(jobsPerMinute))
+	return rl
+}
+
+func (rl *rateLimiter) refill(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rl.done:
+			return
+		case <-ticker.C:
+			select {
+			case rl.tokens <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (rl *rateLimiter) stop() {
+	if rl == nil {
+		return
+	}
+	close(rl.done)
+}
+
+// runBatchPool runs scenesToProcess through the shared backend, up to
+// maxParallel scenes transcribing concurrently (maxParallel <= 1 reproduces
+// the original fully-serialized behaviour) and throttled by jobsPerMinute.
+// It stops dispatching new work as soon as a.stopping flips, marking
+// whatever hasn't started yet as skipped, then waits for in-flight workers
+// to drain before returning.
+func (a *autoCaptionAPI) runBatchPool(ctx context.Context, scenesToProcess []SceneForBatch, supportedLangTags []TagFragment, backend backends.CaptionBackend, serviceURL string, cooldownSeconds, maxParallel, jobsPerMinute int, strategy languageDetectionStrategy, prober backends.LanguageProber) batchSummary {
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	limiter := newRateLimiter(jobsPerMinute)
+	defer limiter.stop()
+
+	summary := batchSummary{Queued: len(scenesToProcess)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallel)
+
+	for _, scene := range scenesToProcess {
+		if a.stopping {
+			sceneTitle := "Unknown"
+			if scene.Title != nil {
+				sceneTitle = *scene.Title
+			}
+			mu.Lock()
+			summary.record(batchSceneResult{SceneID: string(scene.ID), Title: sceneTitle, Status: "skipped", Error: "plugin stopping"})
+			mu.Unlock()
+			continue
+		}
+
+		if err := limiter.wait(ctx); err != nil {
+			sceneTitle := "Unknown"
+			if scene.Title != nil {
+				sceneTitle = *scene.Title
+			}
+			mu.Lock()
+			summary.record(batchSceneResult{SceneID: string(scene.ID), Title: sceneTitle, Status: "skipped", Error: err.Error()})
+			mu.Unlock()
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		scene := scene
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := a.queueSceneCaption(ctx, scene, supportedLangTags, backend, serviceURL, cooldownSeconds, strategy, prober)
+
+			mu.Lock()
+			summary.record(result)
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return summary
+}
+
+// queueSceneCaption resolves a scene's language and video path and runs it
+// through runCaptionJob on the shared backend, reporting the outcome as a
+// batchSceneResult.
+func (a *autoCaptionAPI) queueSceneCaption(ctx context.Context, scene SceneForBatch, supportedLangTags []TagFragment, backend backends.CaptionBackend, serviceURL string, cooldownSeconds int, strategy languageDetectionStrategy, prober backends.LanguageProber) batchSceneResult {
+	ctx = logging.WithSceneID(ctx, string(scene.ID))
+
+	sceneTitle := "Unknown"
+	if scene.Title != nil {
+		sceneTitle = *scene.Title
+	}
+
+	language := a.resolveSceneLanguage(ctx, &scene, supportedLangTags, strategy, prober)
+	if language == "" {
+		logging.WithContext(ctx).Warnf("Scene %s (%s): Could not detect language, skipping", string(scene.ID), sceneTitle)
+		return batchSceneResult{SceneID: string(scene.ID), Title: sceneTitle, Status: "skipped", Error: "could not detect language"}
+	}
+
+	ctx = logging.WithLanguage(ctx, language)
+
+	if len(scene.Files) == 0 {
+		logging.WithContext(ctx).Warnf("Scene %s (%s): No video files found, skipping", string(scene.ID), sceneTitle)
+		return batchSceneResult{SceneID: string(scene.ID), Title: sceneTitle, Language: language, Status: "skipped", Error: "no video files found"}
+	}
+
+	videoPath := scene.Files[0].Path
+
+	if err := a.runCaptionJob(ctx, string(scene.ID), videoPath, language, []string{"en"}, serviceURL, backend, cooldownSeconds); err != nil {
+		logging.WithContext(ctx).Errorf("Scene %s (%s): Failed to generate caption: %v", string(scene.ID), sceneTitle, err)
+		return batchSceneResult{SceneID: string(scene.ID), Title: sceneTitle, Language: language, Status: "failed", Error: err.Error()}
+	}
+
+	logging.WithContext(ctx).Infof("Scene %s (%s): Caption generated (language: %s)", string(scene.ID), sceneTitle, language)
+	return batchSceneResult{SceneID: string(scene.ID), Title: sceneTitle, Language: language, Status: "succeeded"}
+}
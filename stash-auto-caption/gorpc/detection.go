@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+
+	"github.com/stashapp/stash/pkg/plugin/common/log"
+	"stash-auto-caption-rpc/backends"
+)
+
+// languageDetectionStrategy selects how a scene's source language is
+// resolved during batch processing, since not every library tags every
+// scene with a "<Lang> Language" tag.
+type languageDetectionStrategy string
+
+const (
+	// detectByTags is the original behaviour: match a scene's tags against
+	// the "Foreign Language" tag hierarchy.
+	detectByTags languageDetectionStrategy = "tags"
+	// detectByFilename matches a scene's video file name against each
+	// language's configured filename_patterns.
+	detectByFilename languageDetectionStrategy = "filename"
+	// detectByProbe asks the backend to detect the language from a short
+	// audio sample.
+	detectByProbe languageDetectionStrategy = "probe"
+	// detectAuto tries tags, then filename, then probe, using whichever
+	// strategy resolves a language first.
+	detectAuto languageDetectionStrategy = "auto"
+)
+
+// parseLanguageDetectionStrategy normalizes the language_detection plugin
+// arg, defaulting to the original tag-hierarchy behaviour for unknown or
+// empty values.
+func parseLanguageDetectionStrategy(arg string) languageDetectionStrategy {
+	switch languageDetectionStrategy(arg) {
+	case detectByTags, detectByFilename, detectByProbe, detectAuto:
+		return languageDetectionStrategy(arg)
+	default:
+		return detectByTags
+	}
+}
+
+// detectLanguageFromFilename matches each active language's filename
+// patterns against a scene's video file names, e.g. a `\.ja\.` pattern
+// mapping "scene.ja.mp4" to "ja".
+func (a *autoCaptionAPI) detectLanguageFromFilename(scene *SceneForBatch) string {
+	if a.languages == nil {
+		return ""
+	}
+
+	for _, file := range scene.Files {
+		name := filepath.Base(file.Path)
+		for _, entry := range a.languages.Entries() {
+			for _, pattern := range entry.FilenamePatterns {
+				re, err := regexp.Compile("(?i)" + pattern)
+				if err != nil {
+					log.Warnf("Invalid filename_pattern %q for language %q: %v", pattern, entry.Name, err)
+					continue
+				}
+				if re.MatchString(name) {
+					return entry.WhisperCode
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// detectLanguageFromProbe asks prober to detect the scene's spoken language
+// from a short audio sample, caching the result per scene so repeated batch
+// runs don't re-probe scenes that were already resolved.
+func (a *autoCaptionAPI) detectLanguageFromProbe(ctx context.Context, scene *SceneForBatch, prober backends.LanguageProber) string {
+	if prober == nil || len(scene.Files) == 0 {
+		return ""
+	}
+
+	sceneID := string(scene.ID)
+
+	a.probeCacheMu.Lock()
+	if cached, ok := a.probeCache[sceneID]; ok {
+		a.probeCacheMu.Unlock()
+		return cached
+	}
+	a.probeCacheMu.Unlock()
+
+	language, err := prober.DetectLanguage(ctx, scene.Files[0].Path)
+	if err != nil {
+		log.Warnf("Scene %s: language probe failed: %v", sceneID, err)
+		return ""
+	}
+
+	a.probeCacheMu.Lock()
+	if a.probeCache == nil {
+		a.probeCache = make(map[string]string)
+	}
+	a.probeCache[sceneID] = language
+	a.probeCacheMu.Unlock()
+
+	return language
+}
+
+// resolveSceneLanguage dispatches to the configured detection strategy,
+// falling back through tags -> filename -> probe for detectAuto.
+func (a *autoCaptionAPI) resolveSceneLanguage(ctx context.Context, scene *SceneForBatch, supportedLangTags []TagFragment, strategy languageDetectionStrategy, prober backends.LanguageProber) string {
+	switch strategy {
+	case detectByFilename:
+		return a.detectLanguageFromFilename(scene)
+	case detectByProbe:
+		return a.detectLanguageFromProbe(ctx, scene, prober)
+	case detectAuto:
+		if lang := a.detectSceneLanguage(scene, supportedLangTags); lang != "" {
+			return lang
+		}
+		if lang := a.detectLanguageFromFilename(scene); lang != "" {
+			return lang
+		}
+		return a.detectLanguageFromProbe(ctx, scene, prober)
+	default:
+		return a.detectSceneLanguage(scene, supportedLangTags)
+	}
+}
@@ -0,0 +1,23 @@
+package languages
+
+// defaultConfiguration returns the plugin's built-in language list, matching
+// what used to be hard-coded in LANG_DICT. Deployments override it entirely
+// by dropping a languages.toml next to the plugin YAML.
+func defaultConfiguration() Configuration {
+	return Configuration{
+		Language: []LanguageEntry{
+			{Name: "English", ISO6391: "en", WhisperCode: "en", TagNames: []string{"English Language"}, FilenamePatterns: []string{`\.en\.`}},
+			{Name: "Spanish", ISO6391: "es", WhisperCode: "es", TagNames: []string{"Spanish Language"}, FilenamePatterns: []string{`\.es\.`}},
+			{Name: "French", ISO6391: "fr", WhisperCode: "fr", TagNames: []string{"French Language"}, FilenamePatterns: []string{`\.fr\.`}},
+			{Name: "German", ISO6391: "de", WhisperCode: "de", TagNames: []string{"German Language"}, FilenamePatterns: []string{`\.de\.`}},
+			{Name: "Italian", ISO6391: "it", WhisperCode: "it", TagNames: []string{"Italian Language"}, FilenamePatterns: []string{`\.it\.`}},
+			{Name: "Portuguese", ISO6391: "pt", WhisperCode: "pt", TagNames: []string{"Portuguese Language"}, FilenamePatterns: []string{`\.pt\.`}},
+			{Name: "Russian", ISO6391: "ru", WhisperCode: "ru", TagNames: []string{"Russian Language"}, FilenamePatterns: []string{`\.ru\.`}},
+			{Name: "Dutch", ISO6391: "nl", WhisperCode: "nl", TagNames: []string{"Dutch Language"}, FilenamePatterns: []string{`\.nl\.`}},
+			{Name: "Japanese", ISO6391: "ja", WhisperCode: "ja", TagNames: []string{"Japanese Language"}, FilenamePatterns: []string{`\.ja\.`}},
+			{Name: "Chinese", ISO6391: "zh", WhisperCode: "zh", TagNames: []string{"Chinese Language"}, FilenamePatterns: []string{`\.zh\.`}},
+			{Name: "Korean", ISO6391: "ko", WhisperCode: "ko", TagNames: []string{"Korean Language"}, FilenamePatterns: []string{`\.ko\.`}},
+			{Name: "Arabic", ISO6391: "ar", WhisperCode: "ar", TagNames: []string{"Arabic Language"}, FilenamePatterns: []string{`\.ar\.`}},
+		},
+	}
+}
@@ -0,0 +1,150 @@
+// Package languages loads the plugin's language registry from an external
+// languages.toml manifest, modeled on the tree-sitter grammar-manifest
+// approach: a fixed set of built-in entries that a deployment can extend,
+// override, or restrict without recompiling the plugin.
+package languages
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// LanguageEntry describes one language the plugin can detect and caption.
+type LanguageEntry struct {
+	Name        string   `toml:"name"`
+	Aliases     []string `toml:"aliases"`
+	ISO6391     string   `toml:"iso639_1"`
+	ISO6393     string   `toml:"iso639_3"`
+	WhisperCode string   `toml:"whisper_code"`
+	TagNames    []string `toml:"tag_names"`
+	// FilenamePatterns are regexes matched against a scene's video file
+	// name for the "filename" language detection strategy, e.g.
+	// `\.ja\.` matching "scene.ja.mp4".
+	FilenamePatterns []string `toml:"filename_patterns"`
+}
+
+// UseLanguages restricts the active language set to Only (if non-empty),
+// otherwise to everything except Except.
+type UseLanguages struct {
+	Only   []string `toml:"only"`
+	Except []string `toml:"except"`
+}
+
+// Configuration is the root of a languages.toml manifest.
+type Configuration struct {
+	Language     []LanguageEntry `toml:"language"`
+	UseLanguages UseLanguages    `toml:"use-languages"`
+}
+
+// manifestName is the file a deployment drops next to the plugin YAML to
+// override the built-in language list.
+const manifestName = "languages.toml"
+
+// Registry resolves tag names to a LanguageEntry.
+type Registry struct {
+	entries []LanguageEntry
+}
+
+// Load builds a Registry from pluginDir/languages.toml if present, falling
+// back to the built-in defaults otherwise, then applies the manifest's
+// use-languages selection.
+func Load(pluginDir string) (*Registry, error) {
+	cfg := defaultConfiguration()
+
+	if pluginDir != "" {
+		overridePath := filepath.Join(pluginDir, manifestName)
+		if _, err := os.Stat(overridePath); err == nil {
+			var override Configuration
+			if _, err := toml.DecodeFile(overridePath, &override); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", overridePath, err)
+			}
+			cfg = override
+		}
+	}
+
+	if err := validate(cfg.Language); err != nil {
+		return nil, err
+	}
+
+	return &Registry{entries: selectLanguages(cfg.Language, cfg.UseLanguages)}, nil
+}
+
+func validate(entries []LanguageEntry) error {
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "" {
+			return fmt.Errorf("languages.toml: entry missing name")
+		}
+		if entry.WhisperCode == "" {
+			return fmt.Errorf("languages.toml: language %q missing whisper_code", entry.Name)
+		}
+		if seen[entry.Name] {
+			return fmt.Errorf("languages.toml: duplicate language entry %q", entry.Name)
+		}
+		seen[entry.Name] = true
+	}
+	return nil
+}
+
+func selectLanguages(entries []LanguageEntry, use UseLanguages) []LanguageEntry {
+	if len(use.Only) > 0 {
+		only := toSet(use.Only)
+		filtered := make([]LanguageEntry, 0, len(entries))
+		for _, entry := range entries {
+			if only[entry.Name] {
+				filtered = append(filtered, entry)
+			}
+		}
+		return filtered
+	}
+
+	if len(use.Except) > 0 {
+		except := toSet(use.Except)
+		filtered := make([]LanguageEntry, 0, len(entries))
+		for _, entry := range entries {
+			if !except[entry.Name] {
+				filtered = append(filtered, entry)
+			}
+		}
+		return filtered
+	}
+
+	return entries
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// ResolveLanguage matches tagName against every active language's name and
+// aliases, after stripping a trailing " Language" suffix (e.g. "Spanish
+// Language" -> "Spanish").
+func (r *Registry) ResolveLanguage(tagName string) (LanguageEntry, bool) {
+	stripped := strings.TrimSuffix(tagName, " Language")
+
+	for _, entry := range r.entries {
+		if strings.EqualFold(entry.Name, stripped) {
+			return entry, true
+		}
+		for _, alias := range entry.Aliases {
+			if strings.EqualFold(alias, stripped) {
+				return entry, true
+			}
+		}
+	}
+
+	return LanguageEntry{}, false
+}
+
+// Entries returns every active language entry.
+func (r *Registry) Entries() []LanguageEntry {
+	return r.entries
+}
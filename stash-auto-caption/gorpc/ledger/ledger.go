@@ -0,0 +1,140 @@
+// Package ledger persists in-flight caption jobs to a small JSON file so a
+// crashed or restarted plugin process can reattach to them instead of
+// re-queueing expensive whisper jobs from scratch. A flat JSON file is
+// plenty for the handful of jobs a single plugin process tracks at once;
+// it's read once on startup and rewritten on every mutation.
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is a single in-flight caption job.
+type Entry struct {
+	SceneID    string    `json:"scene_id"`
+	VideoPath  string    `json:"video_path"`
+	TaskID     string    `json:"task_id"`
+	ServiceURL string    `json:"service_url"`
+	Backend    string    `json:"backend"`
+	StartedAt  time.Time `json:"started_at"`
+	LastStatus string    `json:"last_status"`
+}
+
+// Ledger tracks open caption jobs, keyed by task ID, and keeps path's
+// contents in sync with every mutation.
+type Ledger struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]Entry
+}
+
+// DefaultPath is where the ledger lives when a deployment doesn't configure
+// a ledger_path plugin arg.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".stash", "plugins", "auto-caption", "jobs.json")
+}
+
+// Open loads path's existing entries, if any, treating a missing file as an
+// empty ledger rather than an error.
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{path: path, entries: map[string]Entry{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, fmt.Errorf("failed to read job ledger %s: %w", path, err)
+	}
+
+	if len(data) == 0 {
+		return l, nil
+	}
+
+	if err := json.Unmarshal(data, &l.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse job ledger %s: %w", path, err)
+	}
+
+	return l, nil
+}
+
+// Put records a new job, or overwrites an existing entry for the same task
+// ID (e.g. a status update).
+func (l *Ledger) Put(entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[entry.TaskID] = entry
+	return l.save()
+}
+
+// UpdateStatus updates an open entry's LastStatus, doing nothing if taskID
+// isn't tracked (e.g. it was already deleted by a concurrent completion).
+func (l *Ledger) UpdateStatus(taskID, status string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[taskID]
+	if !ok {
+		return nil
+	}
+
+	entry.LastStatus = status
+	l.entries[taskID] = entry
+	return l.save()
+}
+
+// Delete removes taskID from the ledger, e.g. once its job completes or
+// fails permanently.
+func (l *Ledger) Delete(taskID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.entries[taskID]; !ok {
+		return nil
+	}
+
+	delete(l.entries, taskID)
+	return l.save()
+}
+
+// Open returns every currently tracked job, in no particular order.
+func (l *Ledger) Open() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// save rewrites the ledger file with the current entries. Callers must hold
+// l.mu.
+func (l *Ledger) save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create job ledger directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal job ledger: %w", err)
+	}
+
+	tmpPath := l.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write job ledger: %w", err)
+	}
+
+	return os.Rename(tmpPath, l.path)
+}
@@ -0,0 +1,129 @@
+// Package logging wraps stash's plugin log package with context-carried
+// correlation fields (batch ID, scene ID, language, job ID) so that
+// concurrent batch runs can be grepped by a single scene's full lifecycle,
+// while still emitting lines through stash's SOH-framed log protocol so the
+// stash UI continues to level-parse them correctly.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/plugin/common/log"
+)
+
+type ctxKey struct{}
+
+// fieldOrder fixes the order correlation fields are rendered in, regardless
+// of the order WithBatchID/WithSceneID/etc. were called.
+var fieldOrder = []string{"batch_id", "scene_id", "language", "job_id"}
+
+type fields map[string]string
+
+func withField(ctx context.Context, key, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+
+	existing, _ := ctx.Value(ctxKey{}).(fields)
+	next := make(fields, len(existing)+1)
+	for k, v := range existing {
+		next[k] = v
+	}
+	next[key] = value
+	return context.WithValue(ctx, ctxKey{}, next)
+}
+
+// WithBatchID attaches a batch ID to ctx for every log line emitted via
+// WithContext(ctx).
+func WithBatchID(ctx context.Context, batchID string) context.Context {
+	return withField(ctx, "batch_id", batchID)
+}
+
+// WithSceneID attaches a scene ID to ctx for every log line emitted via
+// WithContext(ctx).
+func WithSceneID(ctx context.Context, sceneID string) context.Context {
+	return withField(ctx, "scene_id", sceneID)
+}
+
+// WithLanguage attaches a language code to ctx for every log line emitted
+// via WithContext(ctx).
+func WithLanguage(ctx context.Context, language string) context.Context {
+	return withField(ctx, "language", language)
+}
+
+// WithJobID attaches a job ID to ctx for every log line emitted via
+// WithContext(ctx).
+func WithJobID(ctx context.Context, jobID string) context.Context {
+	return withField(ctx, "job_id", jobID)
+}
+
+// Entry logs through stash's common/log package, prefixing every message
+// with whatever correlation fields have been attached to the context it was
+// built from.
+type Entry struct {
+	prefix string
+}
+
+// WithContext returns an Entry carrying whatever correlation fields have
+// been attached to ctx via WithBatchID/WithSceneID/WithLanguage/WithJobID,
+// falling back to a bare entry if none have been set.
+func WithContext(ctx context.Context) *Entry {
+	f, _ := ctx.Value(ctxKey{}).(fields)
+	if len(f) == 0 {
+		return &Entry{}
+	}
+
+	var parts []string
+	for _, key := range fieldOrder {
+		if v, ok := f[key]; ok {
+			parts = append(parts, key+"="+v)
+		}
+	}
+	if len(parts) == 0 {
+		return &Entry{}
+	}
+
+	return &Entry{prefix: "[" + strings.Join(parts, " ") + "] "}
+}
+
+func (e *Entry) Trace(args ...interface{}) {
+	log.Trace(e.prefix + fmt.Sprint(args...))
+}
+
+func (e *Entry) Tracef(format string, args ...interface{}) {
+	log.Trace(e.prefix + fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Debug(args ...interface{}) {
+	log.Debug(e.prefix + fmt.Sprint(args...))
+}
+
+func (e *Entry) Debugf(format string, args ...interface{}) {
+	log.Debug(e.prefix + fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Info(args ...interface{}) {
+	log.Info(e.prefix + fmt.Sprint(args...))
+}
+
+func (e *Entry) Infof(format string, args ...interface{}) {
+	log.Info(e.prefix + fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Warn(args ...interface{}) {
+	log.Warn(e.prefix + fmt.Sprint(args...))
+}
+
+func (e *Entry) Warnf(format string, args ...interface{}) {
+	log.Warn(e.prefix + fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Error(args ...interface{}) {
+	log.Error(e.prefix + fmt.Sprint(args...))
+}
+
+func (e *Entry) Errorf(format string, args ...interface{}) {
+	log.Error(e.prefix + fmt.Sprintf(format, args...))
+}
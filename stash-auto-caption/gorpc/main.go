@@ -1,21 +1,27 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	graphql "github.com/hasura/go-graphql-client"
+	"github.com/google/uuid"
 	"github.com/stashapp/stash/pkg/plugin/common"
 	"github.com/stashapp/stash/pkg/plugin/common/log"
 	"github.com/stashapp/stash/pkg/plugin/util"
+
+	"stash-auto-caption-rpc/backends"
+	"stash-auto-caption-rpc/languages"
+	"stash-auto-caption-rpc/ledger"
+	"stash-auto-caption-rpc/logging"
 )
 
 func main() {
@@ -29,6 +35,14 @@ type autoCaptionAPI struct {
 	stopping         bool
 	serverConnection common.StashServerConnection
 	graphqlClient    *graphql.Client
+	languages        *languages.Registry
+	jobLedger        *ledger.Ledger
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+
+	probeCacheMu sync.Mutex
+	probeCache   map[string]string
 }
 
 // resolveServiceURL resolves the service URL with proper DNS lookup
@@ -103,9 +117,20 @@ func resolveServiceURL(configuredURL string) string {
 	return resolvedURL
 }
 
+// Stop is invoked over a separate RPC call while Run is in flight; it
+// cancels Run's outer context so in-flight HTTP calls (polling, SSE,
+// websocket) are aborted immediately instead of only being noticed between
+// ticks.
 func (a *autoCaptionAPI) Stop(input struct{}, output *bool) error {
 	log.Info("Stopping auto-caption plugin...")
 	a.stopping = true
+
+	a.runMu.Lock()
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.runMu.Unlock()
+
 	*output = true
 	return nil
 }
@@ -116,17 +141,58 @@ func (a *autoCaptionAPI) Run(input common.PluginInput, output *common.PluginOutp
 	a.serverConnection = input.ServerConnection
 	a.graphqlClient = util.NewClient(input.ServerConnection)
 
+	registry, err := languages.Load(input.ServerConnection.PluginDir)
+	if err != nil {
+		errStr := fmt.Sprintf("failed to load language registry: %v", err)
+		*output = common.PluginOutput{Error: &errStr}
+		return nil
+	}
+	a.languages = registry
+
+	ledgerPath := input.Args.String("ledger_path")
+	if ledgerPath == "" {
+		ledgerPath = ledger.DefaultPath()
+	}
+	jobLedger, err := ledger.Open(ledgerPath)
+	if err != nil {
+		errStr := fmt.Sprintf("failed to open job ledger: %v", err)
+		*output = common.PluginOutput{Error: &errStr}
+		return nil
+	}
+	a.jobLedger = jobLedger
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.runMu.Lock()
+	a.cancel = cancel
+	a.runMu.Unlock()
+	defer func() {
+		a.runMu.Lock()
+		a.cancel = nil
+		a.runMu.Unlock()
+		cancel()
+	}()
+
 	mode := input.Args.String("mode")
 
-	var err error
 	var outputStr string = "Unknown mode. Plugin did not run."
 	switch mode {
 	case "generate":
-		err = a.generateCaption(input)
+		err = a.generateCaption(ctx, input)
 		outputStr = "Caption generation completed successfully"
 	case "generateBatch":
-		err = a.generateBatchCaptions(input)
-		outputStr = "Caption generation started successfully"
+		outputStr, err = a.generateBatchCaptions(ctx, input)
+	case "resume":
+		// Reattach to any job left open by a prior crash, so a long-running
+		// whisper job isn't silently re-queued from scratch. Only run here,
+		// on this explicit startup/recovery invocation, rather than on
+		// every generate/generateBatch call, since concurrent
+		// batch-dispatched subprocesses racing to reattach the same ledger
+		// entries would otherwise double-stream the same task.
+		a.resumeOpenJobs(ctx)
+		outputStr = "Reattached to open caption jobs"
+	case "cancel":
+		err = a.cancelOpenJobs(ctx)
+		outputStr = "Cancelled open caption jobs"
 	default:
 		err = fmt.Errorf("unknown mode: %s", mode)
 	}
@@ -146,8 +212,9 @@ func (a *autoCaptionAPI) Run(input common.PluginInput, output *common.PluginOutp
 	return nil
 }
 
-// generateCaption calls the auto-caption web service and polls for completion
-func (a *autoCaptionAPI) generateCaption(input common.PluginInput) error {
+// generateCaption selects a CaptionBackend and runs it to completion for a
+// single scene.
+func (a *autoCaptionAPI) generateCaption(ctx context.Context, input common.PluginInput) error {
 	// Get parameters from input
 	sceneID := input.Args.String("scene_id")
 	videoPath := input.Args.String("video_path")
@@ -169,275 +236,421 @@ func (a *autoCaptionAPI) generateCaption(input common.PluginInput) error {
 	// Resolve service URL with auto-detection
 	serviceURL = resolveServiceURL(serviceURL)
 
-	log.Infof("Generating caption for scene %s: %s (language: %s)", sceneID, videoPath, language)
+	ctx = logging.WithLanguage(logging.WithSceneID(ctx, sceneID), language)
 
-	// Start caption generation task
-	taskID, err := a.startCaptionTask(serviceURL, videoPath, language, translateTo)
+	backend, err := a.backendForScene(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to select caption backend: %w", err)
+	}
+
+	var translateToLangs []string
+	if translateTo != "" {
+		translateToLangs = strings.Split(translateTo, ",")
+	}
+
+	return a.runCaptionJob(ctx, sceneID, videoPath, language, translateToLangs, serviceURL, backend, cooldownSeconds)
+}
+
+// runCaptionJob drives backend through a single scene's captioning job to
+// completion: start, stream progress, scan the produced captions into stash
+// metadata, optionally auto-tag, and apply the "Subtitled" tag. It is the
+// shared core behind both generateCaption (a single mode=generate RPC call)
+// and the batch worker pool, so a scene captioned via either path goes
+// through identical post-processing.
+func (a *autoCaptionAPI) runCaptionJob(ctx context.Context, sceneID, videoPath, language string, translateTo []string, serviceURL string, backend backends.CaptionBackend, cooldownSeconds int) error {
+	logging.WithContext(ctx).Infof("Generating caption for scene %s: %s (language: %s, backend: %s)", sceneID, videoPath, language, backend.Name())
+
+	req := backends.StartRequest{
+		VideoPath:   videoPath,
+		Language:    language,
+		TranslateTo: translateTo,
+	}
+
+	taskID, err := backend.Start(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to start caption task: %w", err)
 	}
 
-	log.Infof("Caption task started: %s", taskID)
+	ctx = logging.WithJobID(ctx, taskID)
+	logging.WithContext(ctx).Infof("Caption task started: %s", taskID)
+
+	if err := a.jobLedger.Put(ledger.Entry{
+		SceneID:    sceneID,
+		VideoPath:  videoPath,
+		TaskID:     taskID,
+		ServiceURL: serviceURL,
+		Backend:    backend.Name(),
+		StartedAt:  time.Now(),
+		LastStatus: "running",
+	}); err != nil {
+		logging.WithContext(ctx).Warnf("Failed to record job %s in ledger: %v", taskID, err)
+	}
 
-	// Poll for task completion
-	err = a.pollTaskStatus(serviceURL, taskID)
+	captionPaths, err := a.streamCaptionProgress(ctx, backend, taskID)
 	if err != nil {
+		a.jobLedger.Delete(taskID)
 		return err
 	}
 
+	// Trigger metadata scan for every caption produced (the original
+	// transcription plus any translations)
+	if err := a.scanCaptionMetadata(ctx, captionPaths); err != nil {
+		logging.WithContext(ctx).Warnf("Failed to trigger metadata scan: %v", err)
+		// Don't fail the whole task if scan fails
+	}
+
+	// Transcription often surfaces performer/studio names, so optionally
+	// close the loop by auto-tagging the scene's video file.
+	if pluginConfig, err := a.getPluginConfiguration(ctx); err != nil {
+		logging.WithContext(ctx).Debugf("Skipping auto-tag, could not load plugin configuration: %v", err)
+	} else if getBoolSetting(pluginConfig, "AutoTagAfterCaption", false) {
+		if err := a.runAutoTag([]string{videoPath}, AutoTagOptions{}); err != nil {
+			logging.WithContext(ctx).Warnf("Failed to trigger auto-tag: %v", err)
+			// Don't fail the whole task if auto-tag fails
+		}
+	}
+
 	// Caption generation succeeded, add "Subtitled" tag to scene
-	log.Infof("Adding 'Subtitled' tag to scene %s", sceneID)
-	if err := a.addSubtitledTag(sceneID); err != nil {
-		log.Warnf("Failed to add 'Subtitled' tag: %v", err)
+	logging.WithContext(ctx).Infof("Adding 'Subtitled' tag to scene %s", sceneID)
+	if err := a.addSubtitledTag(ctx, sceneID); err != nil {
+		logging.WithContext(ctx).Warnf("Failed to add 'Subtitled' tag: %v", err)
 		// Don't fail the whole task if tag update fails
 	}
 
+	// Only now is the job fully done (caption produced, metadata scanned,
+	// "Subtitled" tag applied) — delete it from the ledger last, the same as
+	// resumeOpenJobs, so a crash mid-post-processing leaves the job behind
+	// for the next resume to retry instead of forgetting it.
+	a.jobLedger.Delete(taskID)
+
 	// Apply cooldown period if specified (for batch processing)
 	if cooldownSeconds > 0 {
-		log.Infof("Cooling down for %d seconds to prevent hardware stress...", cooldownSeconds)
+		logging.WithContext(ctx).Infof("Cooling down for %d seconds to prevent hardware stress...", cooldownSeconds)
 		time.Sleep(time.Duration(cooldownSeconds) * time.Second)
 	}
 
 	return nil
 }
 
-func (a *autoCaptionAPI) startCaptionTask(serviceURL, videoPath, language, translateTo string) (string, error) {
-	url := fmt.Sprintf("%s/auto-caption/start", serviceURL)
+// backendForScene resolves which CaptionBackend to use, preferring an
+// explicit "backend" plugin arg and falling back to the plugin's stash
+// configuration so different scenes can be routed to different backends.
+func (a *autoCaptionAPI) backendForScene(ctx context.Context, input common.PluginInput) (backends.CaptionBackend, error) {
+	name := input.Args.String("backend")
 
-	req := TaskStartRequest{
-		VideoPath: videoPath,
-		Language:  language,
-	}
-	if translateTo != "" {
-		req.TranslateTo = &translateTo
+	cfg := backends.Config{
+		ServiceURL: resolveServiceURL(input.Args.String("service_url")),
+		BinaryPath: input.Args.String("binary_path"),
+		APIKey:     input.Args.String("api_key"),
+		Model:      input.Args.String("model"),
 	}
 
-	reqBody, err := json.Marshal(req)
-	if err != nil {
-		return "", err
+	if name == "" {
+		if pluginConfig, err := a.getPluginConfiguration(ctx); err == nil {
+			if v, ok := pluginConfig["backend"].(string); ok {
+				name = v
+			}
+		}
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(reqBody))
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
+	return backends.New(name, cfg)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
-	}
+// streamCaptionProgress drives backend.Stream (or falls back to polling
+// Status) until the job finishes, surfacing progress via log.Progress, and
+// returns every caption file the job produced.
+func (a *autoCaptionAPI) streamCaptionProgress(ctx context.Context, backend backends.CaptionBackend, taskID string) ([]string, error) {
+	var resultPaths []string
+
+	streamErr := backend.Stream(ctx, taskID, func(progress backends.Progress) {
+		log.Progress(progress.Percent)
+		if progress.Stage != "" {
+			logging.WithContext(ctx).Infof("Stage: %s (%.0f%%)", progress.Stage, progress.Percent*100)
+			if err := a.jobLedger.UpdateStatus(taskID, progress.Stage); err != nil {
+				logging.WithContext(ctx).Warnf("Failed to update job %s in ledger: %v", taskID, err)
+			}
+		}
+		if progress.Done {
+			resultPaths = progress.ResultPaths
+			for _, path := range resultPaths {
+				logging.WithContext(ctx).Infof("Caption saved to: %s", path)
+			}
+		}
+	})
 
-	var taskResp TaskStartResponse
-	if err := json.NewDecoder(resp.Body).Decode(&taskResp); err != nil {
-		return "", err
+	if streamErr != nil {
+		return nil, streamErr
 	}
 
-	return taskResp.TaskID, nil
+	return resultPaths, nil
 }
 
-func (a *autoCaptionAPI) pollTaskStatus(serviceURL, taskID string) error {
-	url := fmt.Sprintf("%s/auto-caption/status/%s", serviceURL, taskID)
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+// resumeOpenJobs reattaches to every job still open in the ledger, e.g.
+// left behind by a plugin process that crashed mid-job or mid-batch. It
+// runs to completion (or failure) for each job before returning, the same
+// as the original generateCaption call that started it, so the scene ends
+// up with its caption scanned in and "Subtitled" tag applied exactly once.
+func (a *autoCaptionAPI) resumeOpenJobs(ctx context.Context) {
+	entries := a.jobLedger.Open()
+	if len(entries) == 0 {
+		return
+	}
+
+	logging.WithContext(ctx).Infof("Reattaching to %d open caption job(s) from the ledger", len(entries))
 
-	for {
-		if a.stopping {
-			return fmt.Errorf("task interrupted")
+	for _, entry := range entries {
+		jobCtx := logging.WithJobID(logging.WithSceneID(ctx, entry.SceneID), entry.TaskID)
+		logging.WithContext(jobCtx).Infof("Reattaching to caption task %s for scene %s", entry.TaskID, entry.SceneID)
+
+		backend, err := backends.New(entry.Backend, backends.Config{ServiceURL: entry.ServiceURL})
+		if err != nil {
+			logging.WithContext(jobCtx).Warnf("Failed to reattach to job %s: %v", entry.TaskID, err)
+			continue
 		}
 
-		select {
-		case <-ticker.C:
-			resp, err := http.Get(url)
-			if err != nil {
-				return fmt.Errorf("failed to get task status: %w", err)
-			}
+		captionPaths, err := a.streamCaptionProgress(jobCtx, backend, entry.TaskID)
+		if err != nil {
+			logging.WithContext(jobCtx).Warnf("Resumed job %s failed: %v", entry.TaskID, err)
+			a.jobLedger.Delete(entry.TaskID)
+			continue
+		}
 
-			var status TaskStatusResponse
-			if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
-				resp.Body.Close()
-				return fmt.Errorf("failed to decode status: %w", err)
-			}
-			resp.Body.Close()
+		if err := a.scanCaptionMetadata(jobCtx, captionPaths); err != nil {
+			logging.WithContext(jobCtx).Warnf("Failed to trigger metadata scan for resumed job %s: %v", entry.TaskID, err)
+		}
+		if err := a.addSubtitledTag(jobCtx, entry.SceneID); err != nil {
+			logging.WithContext(jobCtx).Warnf("Failed to add 'Subtitled' tag for resumed scene %s: %v", entry.SceneID, err)
+		}
 
-			// Update progress
-			log.Progress(status.Progress)
-			if status.Stage != nil {
-				log.Infof("Stage: %s (%.0f%%)", *status.Stage, status.Progress*100)
-			}
+		a.jobLedger.Delete(entry.TaskID)
+	}
+}
 
-			// Check status
-			switch status.Status {
-			case "completed":
-				log.Info("Caption generation completed successfully")
-				var captionPath string
-				if cp, ok := status.Result["caption_path"].(string); ok {
-					captionPath = cp
-					log.Infof("Caption saved to: %s", captionPath)
-				}
+// cancelOpenJobs asks the service to cancel every job still open in the
+// ledger and clears them regardless of whether the cancel request
+// succeeds, since a ledger entry for a job nobody is waiting on anymore is
+// just as stale as one for a job that finished.
+func (a *autoCaptionAPI) cancelOpenJobs(ctx context.Context) error {
+	entries := a.jobLedger.Open()
+	logging.WithContext(ctx).Infof("Cancelling %d open caption job(s)", len(entries))
+
+	client := &http.Client{}
+	var lastErr error
+	for _, entry := range entries {
+		jobCtx := logging.WithJobID(logging.WithSceneID(ctx, entry.SceneID), entry.TaskID)
+		if err := cancelJob(jobCtx, client, entry); err != nil {
+			logging.WithContext(jobCtx).Warnf("Failed to cancel job %s: %v", entry.TaskID, err)
+			lastErr = err
+		}
 
-				// Trigger metadata scan if caption was created
-				if captionPath != "" {
-					if err := a.scanCaptionMetadata(captionPath); err != nil {
-						log.Warnf("Failed to trigger metadata scan: %v", err)
-						// Don't fail the whole task if scan fails
-					}
-				}
+		if err := a.jobLedger.Delete(entry.TaskID); err != nil {
+			logging.WithContext(jobCtx).Warnf("Failed to remove cancelled job %s from ledger: %v", entry.TaskID, err)
+		}
+	}
 
-				return nil
+	return lastErr
+}
 
-			case "failed":
-				if status.Error != nil {
-					return fmt.Errorf("caption generation failed: %s", *status.Error)
-				}
-				return fmt.Errorf("caption generation failed")
+// cancelJob POSTs entry's cancel endpoint on the service that started it.
+func cancelJob(ctx context.Context, client *http.Client, entry ledger.Entry) error {
+	url := fmt.Sprintf("%s/auto-caption/cancel/%s", entry.ServiceURL, entry.TaskID)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
 
-			case "queued", "running":
-				// Continue polling
-				continue
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-			default:
-				return fmt.Errorf("unknown task status: %s", status.Status)
-			}
-		}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
 	}
+
+	return nil
 }
 
-// Language dictionary mapping language names to codes
-var LANG_DICT = map[string]string{
-	"English":    "en",
-	"Spanish":    "es",
-	"French":     "fr",
-	"German":     "de",
-	"Italian":    "it",
-	"Portuguese": "pt",
-	"Russian":    "ru",
-	"Dutch":      "nl",
-	"Japanese":   "ja",
-	"Chinese":    "zh",
-	"Korean":     "ko",
-	"Arabic":     "ar",
+// languageCodes returns the whisper code for every active entry in the
+// plugin's language registry, in a stable order so callers that probe the
+// filesystem per language (e.g. getCaptionPathForScene) do so deterministically.
+func (a *autoCaptionAPI) languageCodes() []string {
+	if a.languages == nil {
+		return nil
+	}
+
+	codes := make([]string, 0, len(a.languages.Entries()))
+	for _, entry := range a.languages.Entries() {
+		if entry.WhisperCode != "" {
+			codes = append(codes, entry.WhisperCode)
+		}
+	}
+	sort.Strings(codes)
+	return codes
 }
 
 // generateBatchCaptions finds all foreign language scenes without captions and queues them
-func (a *autoCaptionAPI) generateBatchCaptions(input common.PluginInput) error {
-	ctx := context.Background()
+func (a *autoCaptionAPI) generateBatchCaptions(ctx context.Context, input common.PluginInput) (string, error) {
 	serviceURL := input.Args.String("service_url")
 	cooldownSeconds := getIntArg(input.Args, "cooldown_seconds", 10)
 	maxBatchSize := getIntArg(input.Args, "max_batch_size", 20)
+	maxParallel := getIntArg(input.Args, "max_parallel", 1)
+	jobsPerMinute := getIntArg(input.Args, "jobs_per_minute", 0)
+	strategy := parseLanguageDetectionStrategy(input.Args.String("language_detection"))
 
-	log.Info("Starting batch caption generation for all foreign language scenes...")
-	log.Infof("Configuration: max_batch_size=%d, cooldown_seconds=%d", maxBatchSize, cooldownSeconds)
+	ctx = logging.WithBatchID(ctx, uuid.NewString())
 
-	// Step 1: Find "Foreign Language" parent tag and its children
+	logging.WithContext(ctx).Info("Starting batch caption generation for all foreign language scenes...")
+	logging.WithContext(ctx).Infof("Configuration: max_batch_size=%d, cooldown_seconds=%d, max_parallel=%d, jobs_per_minute=%d, language_detection=%s", maxBatchSize, cooldownSeconds, maxParallel, jobsPerMinute, strategy)
+
+	// Step 1: Find "Foreign Language" parent tag and its children. The
+	// "tags" strategy requires it; the other strategies resolve language
+	// without it, so a missing tag only narrows the tag-based scene set
+	// rather than failing the whole run.
 	foreignLangTag, foreignLangChildren, err := a.findForeignLanguageTag()
 	if err != nil {
-		return fmt.Errorf("failed to find Foreign Language tag: %w", err)
+		return "", fmt.Errorf("failed to find Foreign Language tag: %w", err)
 	}
 
-	if foreignLangTag == nil {
-		return fmt.Errorf("'Foreign Language' tag not found - please create it in Stash")
+	if foreignLangTag == nil && strategy == detectByTags {
+		return "", fmt.Errorf("'Foreign Language' tag not found - please create it in Stash")
 	}
 
-	log.Debugf("Found 'Foreign Language' tag with %d children", len(foreignLangChildren))
+	logging.WithContext(ctx).Debugf("Found 'Foreign Language' tag with %d children", len(foreignLangChildren))
 
 	// Step 2: Build list of supported language tag IDs
 	supportedLangTags := []TagFragment{}
 	for _, childTag := range foreignLangChildren {
-		// Check if this is a supported language (e.g., "Spanish Language")
-		langName := strings.TrimSuffix(childTag.Name, " Language")
-		if _, ok := LANG_DICT[langName]; ok {
+		// Check if this tag resolves to a known language (e.g. "Spanish Language")
+		if _, ok := a.languages.ResolveLanguage(childTag.Name); ok {
 			supportedLangTags = append(supportedLangTags, childTag)
 		}
 	}
 
-	if len(supportedLangTags) == 0 {
-		return fmt.Errorf("no supported language tags found (e.g., 'Spanish Language', 'Japanese Language')")
+	if len(supportedLangTags) == 0 && strategy == detectByTags {
+		return "", fmt.Errorf("no supported language tags found (e.g., 'Spanish Language', 'Japanese Language')")
 	}
 
-	log.Tracef("Found %d supported language tags: %v", len(supportedLangTags), getSupportedLanguageNames(supportedLangTags))
+	logging.WithContext(ctx).Tracef("Found %d supported language tags: %v", len(supportedLangTags), getSupportedLanguageNames(supportedLangTags))
 
-	// Step 3: Query scenes with any of the foreign language tags
-	scenes, err := a.findScenesWithLanguageTags(supportedLangTags)
-	if err != nil {
-		return fmt.Errorf("failed to find scenes: %w", err)
-	}
+	// Step 3: Gather candidate scenes. The "tags" strategy only looks at
+	// scenes under the foreign-language tag hierarchy (plus movie/group
+	// membership); every other strategy resolves language itself, so it
+	// considers every scene that isn't already marked "Subtitled".
+	var scenes []SceneForBatch
+	if strategy == detectByTags {
+		scenes, err = a.findScenesWithLanguageTags(ctx, supportedLangTags)
+		if err != nil {
+			return "", fmt.Errorf("failed to find scenes: %w", err)
+		}
+
+		seenSceneIDs := map[graphql.ID]bool{}
+		for _, scene := range scenes {
+			seenSceneIDs[scene.ID] = true
+		}
+
+		groups, err := a.findGroupsWithLanguageTags(supportedLangTags)
+		if err != nil {
+			logging.WithContext(ctx).Warnf("Failed to find groups with language tags: %v", err)
+		}
+		for _, group := range groups {
+			for _, scene := range group.Scenes {
+				if !seenSceneIDs[scene.ID] {
+					seenSceneIDs[scene.ID] = true
+					scenes = append(scenes, scene)
+				}
+			}
+		}
 
-	log.Infof("Found %d scenes with foreign language tags", len(scenes))
+		movies, err := a.findMoviesWithLanguageTags(supportedLangTags)
+		if err != nil {
+			logging.WithContext(ctx).Warnf("Failed to find movies with language tags: %v", err)
+		}
+		for _, movie := range movies {
+			for _, scene := range movie.Scenes {
+				if !seenSceneIDs[scene.ID] {
+					seenSceneIDs[scene.ID] = true
+					scenes = append(scenes, scene)
+				}
+			}
+		}
+
+		logging.WithContext(ctx).Infof("Found %d scenes with foreign language tags (including movie/group membership)", len(scenes))
+	} else {
+		scenes, err = a.findScenesWithoutSubtitledTag(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to find scenes: %w", err)
+		}
+
+		logging.WithContext(ctx).Infof("Found %d unsubtitled scenes (language_detection=%s)", len(scenes), strategy)
+	}
 
 	// Step 4: Filter scenes to only those without captions
 	scenesToProcess := []SceneForBatch{}
 	for _, scene := range scenes {
-		hasMetadata, hasFile := a.sceneHasCaption(&scene)
-		if !hasFile {
+		metadataLangs, filesByLang := a.sceneHasCaption(&scene)
+		if len(filesByLang) == 0 {
 			scenesToProcess = append(scenesToProcess, scene)
-		} else if !hasMetadata {
-			captionPath := a.getCaptionPathForScene(&scene)
-			if captionPath != nil && *captionPath != "" {
-				err := a.scanCaptionMetadata(*captionPath)
-				if err != nil {
-					log.Warnf("Failed to trigger metadata scan: %v", err)
-				} else {
-					a.addSubtitledTag(string(scene.ID))
-				}
+			continue
+		}
+
+		// Some caption files may already exist on disk (e.g. produced by a
+		// prior run) without being registered as scene metadata yet - scan
+		// those languages in rather than re-queueing the scene.
+		var unscanned []string
+		for lang, path := range filesByLang {
+			if !metadataLangs[lang] {
+				unscanned = append(unscanned, path)
+			}
+		}
+		if len(unscanned) > 0 {
+			if err := a.scanCaptionMetadata(ctx, unscanned); err != nil {
+				logging.WithContext(ctx).Warnf("Failed to trigger metadata scan: %v", err)
+			} else {
+				a.addSubtitledTag(ctx, string(scene.ID))
 			}
 		}
 	}
 
-	log.Infof("Filtered to %d scenes without captions", len(scenesToProcess))
+	logging.WithContext(ctx).Infof("Filtered to %d scenes without captions", len(scenesToProcess))
 
 	if len(scenesToProcess) == 0 {
-		log.Info("No scenes to process - all foreign language scenes already have captions!")
-		return nil
+		logging.WithContext(ctx).Info("No scenes to process - all foreign language scenes already have captions!")
+		return marshalBatchSummary(batchSummary{}), nil
 	}
 
 	// Apply max batch size limit
 	if len(scenesToProcess) > maxBatchSize {
-		log.Warnf("Found %d scenes to process, but limiting to max_batch_size=%d to prevent hardware stress", len(scenesToProcess), maxBatchSize)
+		logging.WithContext(ctx).Warnf("Found %d scenes to process, but limiting to max_batch_size=%d to prevent hardware stress", len(scenesToProcess), maxBatchSize)
 		scenesToProcess = scenesToProcess[:maxBatchSize]
 	}
 
-	// Step 5: Queue caption generation task for each scene
-	log.Infof("Queueing %d scenes for caption generation...", len(scenesToProcess))
-
-	queued := 0
-	failed := 0
-
-	for _, scene := range scenesToProcess {
-		sceneTitle := "Unknown"
-		if scene.Title != nil {
-			sceneTitle = *scene.Title
-		}
-
-		// Detect language from tags
-		language := a.detectSceneLanguage(&scene, supportedLangTags)
-		if language == "" {
-			log.Warnf("Scene %s (%s): Could not detect language, skipping", string(scene.ID), sceneTitle)
-			failed++
-			continue
-		}
+	// Step 5: Caption each scene directly, up to max_parallel at a time and
+	// throttled by jobs_per_minute. A single backend instance is shared by
+	// every worker, the same as a single mode=generate call would resolve.
+	backend, err := a.backendForScene(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to select caption backend: %w", err)
+	}
 
-		// Get video path
-		if len(scene.Files) == 0 {
-			log.Warnf("Scene %s (%s): No video files found, skipping", string(scene.ID), sceneTitle)
-			failed++
-			continue
-		}
+	logging.WithContext(ctx).Infof("Queueing %d scenes for caption generation via backend %s...", len(scenesToProcess), backend.Name())
 
-		// Queue the task via RunPluginTask
-		_, err := a.runPluginTaskForScene(ctx, &scene, language, serviceURL, cooldownSeconds)
-		if err != nil {
-			log.Errorf("Scene %s (%s): Failed to queue task: %v", string(scene.ID), sceneTitle, err)
-			failed++
+	var prober backends.LanguageProber
+	if strategy == detectByProbe || strategy == detectAuto {
+		if p, ok := backend.(backends.LanguageProber); ok {
+			prober = p
 		} else {
-			log.Infof("Scene %s (%s): Queued for caption generation (language: %s)", string(scene.ID), sceneTitle, language)
-			queued++
+			logging.WithContext(ctx).Warnf("language_detection=%s requested but backend does not support /detect-language", strategy)
 		}
 	}
 
-	log.Infof("Batch processing complete: %d tasks queued, %d failed", queued, failed)
+	summary := a.runBatchPool(ctx, scenesToProcess, supportedLangTags, backend, serviceURL, cooldownSeconds, maxParallel, jobsPerMinute, strategy, prober)
 
-	return nil
+	logging.WithContext(ctx).Infof("Batch processing complete: %d succeeded, %d failed, %d skipped (of %d queued)", summary.Succeeded, summary.Failed, summary.Skipped, summary.Queued)
+
+	return marshalBatchSummary(summary), nil
 }
 
 // getSupportedLanguageNames returns a list of language names for logging
@@ -10,7 +10,10 @@ import (
 	"strings"
 
 	graphql "github.com/hasura/go-graphql-client"
+	"github.com/stashapp/stash/pkg/plugin/common"
 	"github.com/stashapp/stash/pkg/plugin/common/log"
+
+	"stash-auto-caption-rpc/logging"
 )
 
 // findForeignLanguageTag queries all tags and returns the "Foreign Language" tag with its children
@@ -46,9 +49,7 @@ func (a *autoCaptionAPI) findForeignLanguageTag() (*TagWithChildren, []TagFragme
 }
 
 // findScenesWithLanguageTags queries scenes with any of the specified language tags
-func (a *autoCaptionAPI) findScenesWithLanguageTags(languageTags []TagFragment) ([]SceneForBatch, error) {
-	ctx := context.Background()
-
+func (a *autoCaptionAPI) findScenesWithLanguageTags(ctx context.Context, languageTags []TagFragment) ([]SceneForBatch, error) {
 	// Build tag ID list
 	tagIDStrings := []graphql.String{}
 	for _, tag := range languageTags {
@@ -87,21 +88,162 @@ func (a *autoCaptionAPI) findScenesWithLanguageTags(languageTags []TagFragment)
 		return nil, fmt.Errorf("failed to query scenes: %w", err)
 	}
 
-	log.Debugf("FindScenes returned %d scenes (total count: %d)", len(query.FindScenes.Scenes), query.FindScenes.Count)
+	logging.WithContext(ctx).Debugf("FindScenes returned %d scenes (total count: %d)", len(query.FindScenes.Scenes), query.FindScenes.Count)
+
+	return query.FindScenes.Scenes, nil
+}
+
+// findScenesWithoutSubtitledTag queries every scene that hasn't yet been
+// marked "Subtitled", regardless of language tagging. It backs the
+// filename/probe/auto detection strategies, which resolve a scene's
+// language without requiring it to sit under the "Foreign Language" tag
+// hierarchy first.
+func (a *autoCaptionAPI) findScenesWithoutSubtitledTag(ctx context.Context) ([]SceneForBatch, error) {
+	var tagsQuery struct {
+		AllTags []struct {
+			ID   graphql.ID `graphql:"id"`
+			Name string     `graphql:"name"`
+		} `graphql:"allTags"`
+	}
+
+	if err := a.graphqlClient.Query(ctx, &tagsQuery, nil); err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+
+	var subtitledTagID graphql.ID
+	for _, tag := range tagsQuery.AllTags {
+		if strings.EqualFold(tag.Name, "Subtitled") {
+			subtitledTagID = tag.ID
+			break
+		}
+	}
+
+	if subtitledTagID == "" {
+		return nil, fmt.Errorf("'Subtitled' tag not found - please create it in Stash")
+	}
+
+	var query struct {
+		FindScenes FindScenesResult `graphql:"findScenes(filter: $f, scene_filter: $sf)"`
+	}
+
+	perPage := graphql.Int(5000)
+	filterInput := &FindFilterType{PerPage: &perPage}
+
+	tagsInput := &HierarchicalMultiCriterionInput{
+		Value:    []graphql.String{graphql.String(subtitledTagID)},
+		Modifier: "EXCLUDES",
+	}
+	sceneFilterInput := &SceneFilterType{Tags: tagsInput}
+
+	variables := map[string]interface{}{
+		"f":  filterInput,
+		"sf": sceneFilterInput,
+	}
+
+	if err := a.graphqlClient.Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to query scenes: %w", err)
+	}
+
+	logging.WithContext(ctx).Debugf("FindScenes (unsubtitled) returned %d scenes (total count: %d)", len(query.FindScenes.Scenes), query.FindScenes.Count)
 
 	return query.FindScenes.Scenes, nil
 }
 
-// scanCaptionMetadata triggers a metadata scan for the caption file
-func (a *autoCaptionAPI) scanCaptionMetadata(captionPath string) error {
+// findMoviesWithLanguageTags queries movies (stash's legacy grouping entity)
+// tagged with any of the specified language tags, so scenes belonging to a
+// tagged movie are picked up even when the individual scene lacks the tag.
+func (a *autoCaptionAPI) findMoviesWithLanguageTags(languageTags []TagFragment) ([]MovieForBatch, error) {
 	ctx := context.Background()
 
+	tagIDStrings := []graphql.String{}
+	for _, tag := range languageTags {
+		tagIDStrings = append(tagIDStrings, graphql.String(tag.ID))
+	}
+
+	var query struct {
+		FindMovies FindMoviesResult `graphql:"findMovies(filter: $f, movie_filter: $mf)"`
+	}
+
+	perPage := graphql.Int(5000)
+	filterInput := &FindFilterType{PerPage: &perPage}
+
+	depth := graphql.Int(-1)
+	tagsInput := &HierarchicalMultiCriterionInput{
+		Value:    tagIDStrings,
+		Modifier: "INCLUDES",
+		Depth:    &depth,
+	}
+	movieFilterInput := &MovieFilterType{Tags: tagsInput}
+
+	variables := map[string]interface{}{
+		"f":  filterInput,
+		"mf": movieFilterInput,
+	}
+
+	err := a.graphqlClient.Query(ctx, &query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query movies: %w", err)
+	}
+
+	log.Debugf("FindMovies returned %d movies (total count: %d)", len(query.FindMovies.Movies), query.FindMovies.Count)
+
+	return query.FindMovies.Movies, nil
+}
+
+// findGroupsWithLanguageTags is the group-entity equivalent of
+// findMoviesWithLanguageTags, for the servers that renamed movies to groups.
+func (a *autoCaptionAPI) findGroupsWithLanguageTags(languageTags []TagFragment) ([]GroupForBatch, error) {
+	ctx := context.Background()
+
+	tagIDStrings := []graphql.String{}
+	for _, tag := range languageTags {
+		tagIDStrings = append(tagIDStrings, graphql.String(tag.ID))
+	}
+
+	var query struct {
+		FindGroups FindGroupsResult `graphql:"findGroups(filter: $f, group_filter: $gf)"`
+	}
+
+	perPage := graphql.Int(5000)
+	filterInput := &FindFilterType{PerPage: &perPage}
+
+	depth := graphql.Int(-1)
+	tagsInput := &HierarchicalMultiCriterionInput{
+		Value:    tagIDStrings,
+		Modifier: "INCLUDES",
+		Depth:    &depth,
+	}
+	groupFilterInput := &GroupFilterType{Tags: tagsInput}
+
+	variables := map[string]interface{}{
+		"f":  filterInput,
+		"gf": groupFilterInput,
+	}
+
+	err := a.graphqlClient.Query(ctx, &query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query groups: %w", err)
+	}
+
+	log.Debugf("FindGroups returned %d groups (total count: %d)", len(query.FindGroups.Groups), query.FindGroups.Count)
+
+	return query.FindGroups.Groups, nil
+}
+
+// scanCaptionMetadata triggers a metadata scan for one or more caption
+// files, batching every path produced by a job (the original transcription
+// plus any translations) into a single scan instead of one per language.
+func (a *autoCaptionAPI) scanCaptionMetadata(ctx context.Context, captionPaths []string) error {
+	if len(captionPaths) == 0 {
+		return nil
+	}
+
 	var mutation struct {
 		MetadataScan graphql.String `graphql:"metadataScan(input: $input)"`
 	}
 
 	input := ScanMetadataInput{
-		Paths: []string{captionPath},
+		Paths: captionPaths,
 	}
 
 	variables := map[string]interface{}{
@@ -113,14 +255,55 @@ func (a *autoCaptionAPI) scanCaptionMetadata(captionPath string) error {
 		return fmt.Errorf("metadata scan mutation failed: %w", err)
 	}
 
-	log.Infof("Triggered metadata scan for caption: %s", captionPath)
+	logging.WithContext(ctx).Infof("Triggered metadata scan for %d caption file(s): %v", len(captionPaths), captionPaths)
 	return nil
 }
 
-// addSubtitledTag adds the "Subtitled" tag to a scene
-func (a *autoCaptionAPI) addSubtitledTag(sceneID string) error {
+// runAutoTag triggers stash's metadataAutoTag mutation restricted to paths,
+// closing the loop between transcription (which reveals performer names and
+// studio mentions) and stash's tagging subsystem. A nil field in opts
+// matches every entity of that type, mirroring stash's own "*" wildcard.
+func (a *autoCaptionAPI) runAutoTag(paths []string, opts AutoTagOptions) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
 	ctx := context.Background()
 
+	var mutation struct {
+		MetadataAutoTag graphql.String `graphql:"metadataAutoTag(input: $input)"`
+	}
+
+	input := AutoTagMetadataInput{
+		Performers: opts.Performers,
+		Studios:    opts.Studios,
+		Tags:       opts.Tags,
+		Paths:      paths,
+	}
+	if input.Performers == nil {
+		input.Performers = []string{"*"}
+	}
+	if input.Studios == nil {
+		input.Studios = []string{"*"}
+	}
+	if input.Tags == nil {
+		input.Tags = []string{"*"}
+	}
+
+	variables := map[string]interface{}{
+		"input": input,
+	}
+
+	if err := a.graphqlClient.Mutate(ctx, &mutation, variables); err != nil {
+		return fmt.Errorf("auto-tag mutation failed: %w", err)
+	}
+
+	log.Infof("Triggered auto-tag for %d path(s): %v", len(paths), paths)
+	return nil
+}
+
+// addSubtitledTag adds the "Subtitled" tag to a scene
+func (a *autoCaptionAPI) addSubtitledTag(ctx context.Context, sceneID string) error {
 	// First, find the "Subtitled" tag ID
 	var tagsQuery struct {
 		AllTags []struct {
@@ -171,7 +354,7 @@ func (a *autoCaptionAPI) addSubtitledTag(sceneID string) error {
 	}
 
 	if hasSubtitledTag {
-		log.Infof("Scene %s already has 'Subtitled' tag", sceneID)
+		logging.WithContext(ctx).Infof("Scene %s already has 'Subtitled' tag", sceneID)
 		return nil
 	}
 
@@ -203,51 +386,12 @@ func (a *autoCaptionAPI) addSubtitledTag(sceneID string) error {
 		return fmt.Errorf("scene update mutation failed: %w", err)
 	}
 
-	log.Infof("Successfully added 'Subtitled' tag to scene %s", sceneID)
+	logging.WithContext(ctx).Infof("Successfully added 'Subtitled' tag to scene %s", sceneID)
 	return nil
 }
 
-// runPluginTaskForScene queues a caption generation task via GraphQL RunPluginTask
-func (a *autoCaptionAPI) runPluginTaskForScene(ctx context.Context, scene *SceneForBatch, language string, serviceURL string) (string, error) {
-	sceneID := string(scene.ID)
-	videoPath := scene.Files[0].Path
-
-	// Use args_map (newer approach) instead of deprecated args parameter
-	var mutation struct {
-		RunPluginTask graphql.ID `graphql:"runPluginTask(plugin_id: $pid, task_name: $tn, description: $desc, args_map: $am)"`
-	}
-
-	// Build args map
-	argsMap := &Map{
-		"mode":         "generate",
-		"scene_id":     sceneID,
-		"video_path":   videoPath,
-		"language":     language,
-		"translate_to": "en",
-		"service_url":  serviceURL,
-	}
-
-	variables := map[string]interface{}{
-		"pid":  graphql.ID("stash-auto-caption"),
-		"tn":   graphql.String("Generate Caption for Scene"),
-		"desc": graphql.String(fmt.Sprintf("Generating caption for %s", videoPath)),
-		"am":   argsMap,
-	}
-
-	err := a.graphqlClient.Mutate(ctx, &mutation, variables)
-	if err != nil {
-		return "", fmt.Errorf("failed to run plugin task: %w", err)
-	}
-
-	jobID := string(mutation.RunPluginTask)
-	log.Debugf("Queued job ID: %s", jobID)
-
-	return jobID, nil
-}
-
-func (a *autoCaptionAPI) getPluginConfiguration() (PluginConfig, error) {
+func (a *autoCaptionAPI) getPluginConfiguration(ctx context.Context) (PluginConfig, error) {
 	pluginName := "stash-auto-caption"
-	ctx := context.Background()
 
 	query := `query Configuration {
 		configuration {
@@ -269,7 +413,7 @@ func (a *autoCaptionAPI) getPluginConfiguration() (PluginConfig, error) {
 		return nil, fmt.Errorf("failed to unmarshal plugin configuration: %w", err)
 	}
 
-	log.Debugf("Plugin configuration response: %+v", response)
+	logging.WithContext(ctx).Debugf("Plugin configuration response: %+v", response)
 
 	// Look up the plugin configuration by name
 	if pluginConfig, ok := response.Configuration.Plugins[pluginName]; ok {
@@ -279,56 +423,89 @@ func (a *autoCaptionAPI) getPluginConfiguration() (PluginConfig, error) {
 	return nil, fmt.Errorf("plugin configuration not found for '%s'", pluginName)
 }
 
-// sceneHasCaption checks if a scene has caption metadata or an .srt file on disk
-func (a *autoCaptionAPI) sceneHasCaption(scene *SceneForBatch) (bool, bool) {
-	metadata := false
-	file := false
-	// Check 1: Caption metadata exists
-	if len(scene.Captions) > 0 && scene.Paths != nil && scene.Paths.Caption != nil {
-		log.Debugf("Scene %s has caption metadata", string(scene.ID))
-		metadata = true
+// sceneHasCaption reports, per language code, whether a scene already has
+// caption metadata registered in stash and whether a caption file for that
+// language exists on disk (keyed to its path so callers can scan it in).
+func (a *autoCaptionAPI) sceneHasCaption(scene *SceneForBatch) (metadataLangs map[string]bool, filesByLang map[string]string) {
+	metadataLangs = make(map[string]bool)
+	for _, caption := range scene.Captions {
+		metadataLangs[caption.LanguageCode] = true
 	}
 
-	// Check 2: .srt file exists on disk
-	if a.getCaptionPathForScene(scene) != nil {
-		file = true
+	filesByLang = make(map[string]string)
+	for _, caption := range a.getCaptionPathForScene(scene) {
+		filesByLang[caption.Language] = caption.Path
 	}
 
-	return metadata, file
+	return metadataLangs, filesByLang
 }
 
-func (a *autoCaptionAPI) getCaptionPathForScene(scene *SceneForBatch) *string {
-	if len(scene.Files) > 0 {
-		videoPath := scene.Files[0].Path
-		srtPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + ".en.srt"
+// getCaptionPathForScene returns every caption file found on disk for scene,
+// one per language code (e.g. "<video>.en.srt", "<video>.es.srt"), so a
+// scene's original transcription and any translations are reported as
+// first-class siblings rather than assuming a single ".en.srt".
+func (a *autoCaptionAPI) getCaptionPathForScene(scene *SceneForBatch) []CaptionFile {
+	if len(scene.Files) == 0 {
+		return nil
+	}
 
+	videoPath := scene.Files[0].Path
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+
+	var captions []CaptionFile
+	for _, code := range a.languageCodes() {
+		srtPath := fmt.Sprintf("%s.%s.srt", base, code)
 		if _, err := os.Stat(srtPath); err == nil {
 			log.Debugf("Scene %s has .srt file on disk: %s", string(scene.ID), srtPath)
-			return &srtPath
+			captions = append(captions, CaptionFile{Language: code, Path: srtPath})
 		}
 	}
-	return nil
+
+	return captions
 }
 
-// detectSceneLanguage detects the language of a scene based on its tags
+// detectSceneLanguage detects the language of a scene based on its tags. If
+// a scene carries more than one language tag, the match is ambiguous and
+// empty is returned to trigger auto-detect instead of guessing.
 func (a *autoCaptionAPI) detectSceneLanguage(scene *SceneForBatch, supportedLangTags []TagFragment) string {
-	// Find first matching language tag
+	var match string
 	for _, sceneTag := range scene.Tags {
 		for _, langTag := range supportedLangTags {
 			if sceneTag.ID == langTag.ID {
-				// Extract language name (e.g., "Spanish Language" -> "Spanish")
-				langName := strings.TrimSuffix(sceneTag.Name, " Language")
-
-				// Map to language code
-				if code, ok := LANG_DICT[langName]; ok {
-					return code
+				if entry, ok := a.languages.ResolveLanguage(sceneTag.Name); ok {
+					if match != "" && match != entry.WhisperCode {
+						// Multiple distinct language tags found - ambiguous, trigger auto-detect
+						return ""
+					}
+					match = entry.WhisperCode
 				}
 			}
 		}
 	}
 
-	// If multiple language tags found (shouldn't happen), return empty to trigger auto-detect
-	return ""
+	return match
+}
+
+// getIntArg safely retrieves an integer plugin argument, converting from the
+// types common.ArgsMap values arrive as (JSON numbers decode to float64).
+func getIntArg(args common.ArgsMap, key string, defaultValue int) int {
+	v, ok := args[key]
+	if !ok {
+		return defaultValue
+	}
+
+	switch val := v.(type) {
+	case int:
+		return val
+	case float64:
+		return int(val)
+	case string:
+		if parsed, err := strconv.Atoi(val); err == nil {
+			return parsed
+		}
+	}
+
+	return defaultValue
 }
 
 // getIntSetting safely retrieves an integer argument, converting to int if necessary with parsing
@@ -358,3 +535,23 @@ func getIntSetting(setting map[string]interface{}, key string, defaultValue int)
 	// Fallback if type is not recognized or string parsing failed
 	return defaultValue
 }
+
+// getBoolSetting safely retrieves a boolean setting from plugin config,
+// converting from a string if necessary.
+func getBoolSetting(setting map[string]interface{}, key string, defaultValue bool) bool {
+	value, ok := setting[key]
+	if !ok {
+		return defaultValue
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			return parsed
+		}
+	}
+
+	return defaultValue
+}
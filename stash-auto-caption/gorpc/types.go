@@ -28,6 +28,14 @@ type ScenePaths struct {
 	Caption    *string `json:"caption,omitempty" graphql:"caption"`
 }
 
+// CaptionFile is a single caption track found on disk for a scene, keyed by
+// language code so a scene's original transcription and any translations are
+// treated as first-class siblings rather than assuming a single ".en.srt".
+type CaptionFile struct {
+	Language string
+	Path     string
+}
+
 // SceneForBatch represents a scene for batch processing
 type SceneForBatch struct {
 	ID       graphql.ID    `json:"id" graphql:"id"`
@@ -56,6 +64,23 @@ type ScanMetadataInput struct {
 	Paths []string `json:"paths" graphql:"paths"`
 }
 
+// AutoTagMetadataInput represents input for the metadataAutoTag mutation,
+// restricting auto-tagging to a specific set of paths.
+type AutoTagMetadataInput struct {
+	Performers []string `json:"performers,omitempty" graphql:"performers"`
+	Studios    []string `json:"studios,omitempty" graphql:"studios"`
+	Tags       []string `json:"tags,omitempty" graphql:"tags"`
+	Paths      []string `json:"paths,omitempty" graphql:"paths"`
+}
+
+// AutoTagOptions selects which entity types runAutoTag should match against;
+// a nil slice matches everything of that type (stash's "*" wildcard).
+type AutoTagOptions struct {
+	Performers []string
+	Studios    []string
+	Tags       []string
+}
+
 // SceneUpdateInput represents input for updating a scene's tags
 type SceneUpdateInput struct {
 	ID     graphql.ID   `json:"id" graphql:"id"`
@@ -68,29 +93,6 @@ type SceneFragment struct {
 	Tags []*TagFragment "json:\"tags\" graphql:\"tags\""
 }
 
-// TaskStartRequest represents the request to start a caption task
-type TaskStartRequest struct {
-	VideoPath   string  `json:"video_path"`
-	Language    string  `json:"language"`
-	TranslateTo *string `json:"translate_to,omitempty"`
-}
-
-// TaskStartResponse represents the response from starting a task
-type TaskStartResponse struct {
-	TaskID string `json:"task_id"`
-	Status string `json:"status"`
-}
-
-// TaskStatusResponse represents the task status response
-type TaskStatusResponse struct {
-	TaskID   string                 `json:"task_id"`
-	Status   string                 `json:"status"`
-	Progress float64                `json:"progress"`
-	Stage    *string                `json:"stage"`
-	Error    *string                `json:"error"`
-	Result   map[string]interface{} `json:"result"`
-}
-
 // FindFilterType represents filter parameters for finding scenes
 type FindFilterType struct {
 	PerPage *graphql.Int    `graphql:"per_page" json:"per_page"`
@@ -109,6 +111,47 @@ type SceneFilterType struct {
 	Tags *HierarchicalMultiCriterionInput `graphql:"tags" json:"tags"`
 }
 
+// MovieFilterType represents movie-specific filters. Movie is stash's
+// legacy name for what is now called a Group; kept for older servers.
+type MovieFilterType struct {
+	Tags *HierarchicalMultiCriterionInput `graphql:"tags" json:"tags"`
+}
+
+// GroupFilterType represents group-specific filters.
+type GroupFilterType struct {
+	Tags *HierarchicalMultiCriterionInput `graphql:"tags" json:"tags"`
+}
+
+// MovieForBatch mirrors SceneForBatch for stash's legacy "movie" entity: a
+// tagged collection of scenes that may lack the language tag individually.
+type MovieForBatch struct {
+	ID     graphql.ID      `json:"id" graphql:"id"`
+	Name   string          `json:"name" graphql:"name"`
+	Tags   []TagFragment   `json:"tags" graphql:"tags"`
+	Scenes []SceneForBatch `json:"scenes" graphql:"scenes"`
+}
+
+// GroupForBatch mirrors SceneForBatch for stash's "group" entity (the
+// current name for what used to be called a movie).
+type GroupForBatch struct {
+	ID     graphql.ID      `json:"id" graphql:"id"`
+	Name   string          `json:"name" graphql:"name"`
+	Tags   []TagFragment   `json:"tags" graphql:"tags"`
+	Scenes []SceneForBatch `json:"scenes" graphql:"scenes"`
+}
+
+// FindMoviesResult represents the result of a FindMovies query
+type FindMoviesResult struct {
+	Count  graphql.Int
+	Movies []MovieForBatch
+}
+
+// FindGroupsResult represents the result of a FindGroups query
+type FindGroupsResult struct {
+	Count  graphql.Int
+	Groups []GroupForBatch
+}
+
 // PluginArgInput represents an argument for plugin task (deprecated but working)
 type PluginArgInput struct {
 	Key   graphql.String    `graphql:"key" json:"key"`